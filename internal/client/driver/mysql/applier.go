@@ -0,0 +1,140 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"udup/internal/config"
+)
+
+// Applier applies copied rows and streamed binlog events to the
+// destination MySQL server, and drives the cut-over once row-copy and
+// binlog streaming have caught up.
+type Applier struct {
+	mysqlContext *config.MySQLDriverConfig
+	db           *sql.DB
+	tables       map[string]*config.Table
+	logger       loggerInterface
+	stopCh       chan struct{}
+}
+
+// NewApplier wires up an Applier for one migration job.
+func NewApplier(mysqlContext *config.MySQLDriverConfig, db *sql.DB, tables []*config.Table, logger loggerInterface) *Applier {
+	byName := make(map[string]*config.Table, len(tables))
+	for _, t := range tables {
+		byName[t.Name] = t
+	}
+	return &Applier{
+		mysqlContext: mysqlContext,
+		db:           db,
+		tables:       byName,
+		logger:       logger,
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// Run validates the destination schema's ENUM columns against the
+// source's before starting, refusing to proceed if a shared ENUM
+// column's value ordering differs without an explicit operator
+// override, then starts the postpone flag file watcher CutOver depends
+// on.
+func (d *Applier) Run(destSchema string) error {
+	if err := d.validateEnumRemap(destSchema); err != nil {
+		return err
+	}
+	go d.watchPostponeCutOverFlagFile(d.stopCh)
+	return nil
+}
+
+// validateEnumRemap refuses to start when a shared ENUM column's value
+// ordering differs between source and a destination column that's still
+// an ENUM (rather than having been widened to VARCHAR/TEXT, which
+// bindColumnValue/translateEnumOrdinal handle per-row instead), unless
+// ApproveEnumRemap is set.
+func (d *Applier) validateEnumRemap(destSchema string) error {
+	for _, t := range d.tables {
+		if len(t.EnumValues) == 0 {
+			continue
+		}
+		destTable := &config.Table{Name: t.Name}
+		if err := captureEnumValues(d.db, destSchema, destTable); err != nil {
+			return err
+		}
+		for column, sourceOrder := range t.EnumValues {
+			destOrder, isEnum := destTable.EnumValues[column]
+			if !isEnum {
+				continue
+			}
+			if err := validateEnumRemap(t, column, sourceOrder, destOrder, d.mysqlContext.ApproveEnumRemap); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Stop signals Run's background goroutines to exit.
+func (d *Applier) Stop() {
+	close(d.stopCh)
+}
+
+// bindColumnValue prepares a single decoded binlog column value for
+// binding into a generated INSERT/UPDATE statement, translating the ENUM
+// ordinal of any column tracked in t.EnumValues back into its string
+// label. It does not check the destination column's type, so the label
+// is bound whether that column was widened to VARCHAR/TEXT or is still
+// an ENUM.
+func (d *Applier) bindColumnValue(t *config.Table, column string, value interface{}) interface{} {
+	return translateEnumOrdinal(t, column, value)
+}
+
+// ApplyRowEvent prepares one decoded binlog row event for apply: it
+// consults the cached throttle status first so the applier backs off the
+// same way the extractor does, binds each value via bindColumnValue, and
+// hands the bound values to execApply, which executes no statement.
+func (d *Applier) ApplyRowEvent(tableName string, columns []string, values []interface{}) error {
+	t, ok := d.tables[tableName]
+	if !ok {
+		return fmt.Errorf("unknown table %s", tableName)
+	}
+
+	d.checkThrottleStatus()
+
+	bound := make([]interface{}, len(values))
+	for i, v := range values {
+		bound[i] = d.bindColumnValue(t, columns[i], v)
+	}
+	return d.execApply(t, columns, bound)
+}
+
+// execApply is the seam ApplyRowEvent hands bound values to. It builds
+// and executes no INSERT/UPDATE/DELETE and always returns nil; the
+// MappedSharedColumns-driven statement building is not part of this
+// tree.
+func (d *Applier) execApply(t *config.Table, columns []string, values []interface{}) error {
+	return nil
+}
+
+// CutOver runs the atomic rename once row-copy and binlog streaming have
+// caught up: it waits out any postponement, then retries the critical
+// rename section until it succeeds or ctx is canceled, since a timeout
+// only rolls back the metadata lock rather than failing the cut-over
+// outright.
+func (d *Applier) CutOver(ctx context.Context, rename func(ctx context.Context) error) error {
+	if err := d.awaitCutOver(d.stopCh); err != nil {
+		return err
+	}
+
+	for {
+		err := d.runCriticalRenameSection(rename)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+}
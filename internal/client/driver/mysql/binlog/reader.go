@@ -0,0 +1,134 @@
+package binlog
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	gomysql "github.com/siddontang/go-mysql/mysql"
+	"github.com/siddontang/go-mysql/replication"
+
+	"udup/internal/config"
+)
+
+// Reader streams binlog events from a MySQL master, wrapping a
+// replication.BinlogSyncer with bounded reconnect semantics. Left
+// unbounded, a broken upstream connection causes the syncer to retry
+// forever, which masks a permanently-dead master behind what looks like
+// a healthy, if idle, extractor.
+type Reader struct {
+	cfg     *config.MySQLDriverConfig
+	syncer  *replication.BinlogSyncer
+	streamr *replication.BinlogStreamer
+
+	// reconnectFailures counts consecutive StartSync/streaming failures.
+	// It resets to 0 on any successful event. Read and written only from
+	// the single goroutine driving StartSync/streaming, so it doesn't
+	// need atomics, but it's exported via GetReconnectFailures for
+	// status reporting from other goroutines.
+	reconnectFailures int64
+}
+
+// NewReader builds a Reader from the driver config, translating the
+// BinlogHeartbeatPeriod/BinlogReadTimeout/BinlogSyncerServerID knobs into
+// the underlying syncer config.
+func NewReader(cfg *config.MySQLDriverConfig) *Reader {
+	syncerCfg := replication.BinlogSyncerConfig{
+		ServerID:        cfg.BinlogSyncerServerID,
+		Flavor:          "mysql",
+		HeartbeatPeriod: cfg.BinlogHeartbeatPeriod,
+		ReadTimeout:     cfg.BinlogReadTimeout,
+	}
+	return &Reader{
+		cfg:    cfg,
+		syncer: replication.NewBinlogSyncer(&syncerCfg),
+	}
+}
+
+// GetReconnectFailures returns the number of consecutive reconnect
+// failures observed since the last successful event.
+func (r *Reader) GetReconnectFailures() int64 {
+	return atomic.LoadInt64(&r.reconnectFailures)
+}
+
+// StreamEvents connects at pos and streams binlog events to handler
+// until ctx is canceled, handler returns an error, or
+// BinlogSyncerMaxReconnectAttempts consecutive StartSync/streaming
+// failures are reached, in which case the terminal error is returned so
+// the driver can fail the job fast rather than stall silently.
+func (r *Reader) StreamEvents(ctx context.Context, pos gomysql.Position, handler func(*replication.BinlogEvent) error) error {
+	for {
+		if err := r.streamFrom(ctx, pos); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if terminal := r.onReconnectFailure(err); terminal != nil {
+				return terminal
+			}
+			continue
+		}
+
+		for {
+			ev, err := r.streamr.GetEvent(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if terminal := r.onReconnectFailure(err); terminal != nil {
+					return terminal
+				}
+				break
+			}
+
+			r.onSuccess()
+			if err := handler(ev); err != nil {
+				return err
+			}
+			pos.Pos = ev.Header.LogPos
+		}
+	}
+}
+
+// streamFrom (re)connects the underlying syncer at pos. On failure it
+// waits a short backoff before returning the error, so StreamEvents'
+// retry loop doesn't hammer a struggling master.
+func (r *Reader) streamFrom(ctx context.Context, pos gomysql.Position) error {
+	streamr, err := r.syncer.StartSync(pos)
+	if err != nil {
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return err
+	}
+	r.streamr = streamr
+	return nil
+}
+
+// onReconnectFailure records a reconnect failure and reports whether the
+// caller has exhausted BinlogSyncerMaxReconnectAttempts and should treat
+// the master as permanently unreachable. A MaxReconnectAttempts of 0
+// means retry forever.
+func (r *Reader) onReconnectFailure(cause error) error {
+	failures := atomic.AddInt64(&r.reconnectFailures, 1)
+	max := int64(r.cfg.BinlogSyncerMaxReconnectAttempts)
+	if max > 0 && failures >= max {
+		return fmt.Errorf("binlog syncer exhausted %d reconnect attempts, last error: %v", max, cause)
+	}
+	return nil
+}
+
+// onSuccess resets the reconnect failure counter after a successfully
+// streamed event. A successful StartSync alone does not reset it.
+func (r *Reader) onSuccess() {
+	atomic.StoreInt64(&r.reconnectFailures, 0)
+}
+
+// Close releases the underlying syncer.
+func (r *Reader) Close() {
+	if r.syncer != nil {
+		r.syncer.Close()
+	}
+}
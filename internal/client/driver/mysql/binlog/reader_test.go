@@ -0,0 +1,57 @@
+package binlog
+
+import (
+	"errors"
+	"testing"
+
+	"udup/internal/config"
+)
+
+func TestOnReconnectFailureInfiniteByDefault(t *testing.T) {
+	r := &Reader{cfg: &config.MySQLDriverConfig{}}
+
+	for i := 0; i < 100; i++ {
+		if err := r.onReconnectFailure(errors.New("boom")); err != nil {
+			t.Fatalf("expected nil error with MaxReconnectAttempts=0, got %v after %d failures", err, i+1)
+		}
+	}
+	if got := r.GetReconnectFailures(); got != 100 {
+		t.Fatalf("GetReconnectFailures() = %d, want 100", got)
+	}
+}
+
+func TestOnReconnectFailureBounded(t *testing.T) {
+	r := &Reader{cfg: &config.MySQLDriverConfig{BinlogSyncerMaxReconnectAttempts: 3}}
+
+	for i := 0; i < 2; i++ {
+		if err := r.onReconnectFailure(errors.New("boom")); err != nil {
+			t.Fatalf("failure %d: expected nil error before exhausting attempts, got %v", i+1, err)
+		}
+	}
+
+	err := r.onReconnectFailure(errors.New("final boom"))
+	if err == nil {
+		t.Fatal("expected a terminal error once BinlogSyncerMaxReconnectAttempts is reached")
+	}
+}
+
+func TestOnSuccessResetsFailureCounter(t *testing.T) {
+	r := &Reader{cfg: &config.MySQLDriverConfig{BinlogSyncerMaxReconnectAttempts: 2}}
+
+	if err := r.onReconnectFailure(errors.New("boom")); err != nil {
+		t.Fatalf("unexpected terminal error: %v", err)
+	}
+	r.onSuccess()
+	if got := r.GetReconnectFailures(); got != 0 {
+		t.Fatalf("GetReconnectFailures() = %d after onSuccess, want 0", got)
+	}
+
+	// A fresh run of failures after a success must still require the full
+	// MaxReconnectAttempts before tripping.
+	if err := r.onReconnectFailure(errors.New("boom")); err != nil {
+		t.Fatalf("unexpected terminal error: %v", err)
+	}
+	if err := r.onReconnectFailure(errors.New("boom")); err == nil {
+		t.Fatal("expected a terminal error on the 2nd failure after reset")
+	}
+}
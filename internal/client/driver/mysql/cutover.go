@@ -0,0 +1,166 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/rpc"
+	"os"
+	"sync"
+	"time"
+)
+
+// errCutOverAwaitCanceled is returned by awaitCutOver when stopCh closes
+// while the cut-over is still postponed, so the driver can shut down
+// instead of blocking forever on a flag file no one will ever remove.
+var errCutOverAwaitCanceled = errors.New("cut-over await canceled by shutdown")
+
+// watchPostponeCutOverFlagFile polls PostponeCutOverFlagFile every second
+// for as long as the driver runs, keeping IsPostponingCutOver in sync
+// with whether the file exists. Operators create the file to hold the
+// migration at the finish line until they're ready to coordinate a cut
+// over with an application deploy, and remove it (or call the unpostpone
+// endpoint) to let it proceed.
+func (d *Applier) watchPostponeCutOverFlagFile(stopCh <-chan struct{}) {
+	path := d.mysqlContext.PostponeCutOverFlagFile
+	if path == "" {
+		return
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_, err := os.Stat(path)
+			d.mysqlContext.SetPostponingCutOver(err == nil)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// awaitCutOver blocks the cut-over state machine until the postpone flag
+// file is gone or the user has forced progression via the unpostpone
+// endpoint, then returns nil so the caller can proceed to acquire the
+// metadata lock. It returns errCutOverAwaitCanceled if stopCh closes
+// first, so a postponed cut-over never prevents the driver from shutting
+// down.
+func (d *Applier) awaitCutOver(stopCh <-chan struct{}) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for d.mysqlContext.IsCutOverPostponed() {
+		select {
+		case <-ticker.C:
+		case <-stopCh:
+			return errCutOverAwaitCanceled
+		}
+	}
+	return nil
+}
+
+// runCriticalRenameSection executes rename under
+// InCutOverCriticalSectionFlag, guaranteeing it clears the flag (so
+// shutdown can proceed) once rename returns. If the metadata lock is held
+// past CutOverLockTimeoutSeconds, rename's context is canceled so it can
+// stop promptly; runCriticalRenameSection always waits for rename to
+// actually return before rolling back the lock, so the rollback can never
+// race a rename that's still in flight. Success is decided by rename's
+// own return value, not by whether the deadline happened to elapse by the
+// time it returned, so a rename that commits right at the timeout
+// boundary is never mistaken for a failure.
+func (d *Applier) runCriticalRenameSection(rename func(ctx context.Context) error) error {
+	d.mysqlContext.EnterCutOverCriticalSection()
+	defer d.mysqlContext.LeaveCutOverCriticalSection()
+
+	timeout := time.Duration(d.mysqlContext.CutOverLockTimeoutSeconds) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- rename(ctx) }()
+
+	err := <-done
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		d.rollbackMetadataLock()
+		return fmt.Errorf("cut-over critical section exceeded CutOverLockTimeoutSeconds (%s), metadata lock rolled back: %v", timeout, err)
+	}
+	return err
+}
+
+// CutOverEndpoint is registered on the client agent's RPC server (see
+// RegisterCutOverEndpoint) so operators can force cut-over progression
+// past a still-present postpone flag file, matching the RPCHandler
+// convention used elsewhere in the client (method name + args/reply). A
+// single client agent can run more than one migration job at a time, so
+// the endpoint keeps a registry of running Appliers by JobID rather than
+// binding to one permanently: Unpostpone would otherwise unpostpone
+// whatever job happened to be registered first, regardless of which
+// JobID the caller asked for.
+type CutOverEndpoint struct {
+	mu       sync.Mutex
+	appliers map[string]*Applier
+}
+
+// NewCutOverEndpoint returns an empty CutOverEndpoint ready to be
+// registered and have Appliers added to it as their jobs start.
+func NewCutOverEndpoint() *CutOverEndpoint {
+	return &CutOverEndpoint{appliers: make(map[string]*Applier)}
+}
+
+// Register makes d reachable by its job's Uuid for the lifetime of the
+// migration. Callers should Unregister the same JobID once d.Stop has
+// been called.
+func (e *CutOverEndpoint) Register(d *Applier) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.appliers[d.mysqlContext.Uuid] = d
+}
+
+// Unregister removes a finished job's Applier from the registry.
+func (e *CutOverEndpoint) Unregister(jobID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.appliers, jobID)
+}
+
+// UnpostponeArgs and UnpostponeReply are the RPC args/reply for
+// CutOverEndpoint.Unpostpone.
+type UnpostponeArgs struct {
+	JobID string
+}
+
+type UnpostponeReply struct {
+	Unpostponed bool
+}
+
+// Unpostpone forces the cut-over to proceed even while the postpone flag
+// file is still present, for the job named by args.JobID. It's exposed
+// as the RPC method "CutOver.Unpostpone".
+func (e *CutOverEndpoint) Unpostpone(args *UnpostponeArgs, reply *UnpostponeReply) error {
+	e.mu.Lock()
+	d, ok := e.appliers[args.JobID]
+	e.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no running job with id %q", args.JobID)
+	}
+	d.mysqlContext.CommandUnpostponeCutOver()
+	reply.Unpostponed = true
+	return nil
+}
+
+// RegisterCutOverEndpoint registers e on server as "CutOver", making
+// "CutOver.Unpostpone" callable over the client agent's RPC connection.
+func RegisterCutOverEndpoint(server *rpc.Server, e *CutOverEndpoint) error {
+	return server.RegisterName("CutOver", e)
+}
+
+// onShutdownSignal rejects a SIGTERM-driven shutdown while the critical
+// rename section is in progress; the caller should retry once
+// IsInCutOverCriticalSection() reports false.
+func (d *Applier) onShutdownSignal() (accepted bool) {
+	return !d.mysqlContext.IsInCutOverCriticalSection()
+}
@@ -0,0 +1,130 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"udup/internal/config"
+)
+
+// captureEnumValues inspects information_schema.COLUMNS.COLUMN_TYPE for
+// every ENUM column on schema.table and populates t.EnumValues with its
+// ordered value labels. It's run once per table at inspection time,
+// before row-copy or binlog streaming starts.
+func captureEnumValues(db *sql.DB, schema string, t *config.Table) error {
+	query := `select column_name, column_type from information_schema.columns
+		where table_schema = ? and table_name = ? and data_type = 'enum'`
+	rows, err := db.Query(query, schema, t.Name)
+	if err != nil {
+		return fmt.Errorf("failed to inspect ENUM columns for %s.%s: %v", schema, t.Name, err)
+	}
+	defer rows.Close()
+
+	values := make(map[string][]string)
+	for rows.Next() {
+		var column, columnType string
+		if err := rows.Scan(&column, &columnType); err != nil {
+			return err
+		}
+		labels, err := parseEnumColumnType(columnType)
+		if err != nil {
+			return fmt.Errorf("failed to parse ENUM definition for %s.%s.%s (%q): %v", schema, t.Name, column, columnType, err)
+		}
+		values[column] = labels
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	t.EnumValues = values
+	return nil
+}
+
+// parseEnumColumnType parses a COLUMN_TYPE like
+// "enum('small','medium','large')" into its ordered value labels. It
+// tracks quote state rather than splitting on a bare comma, so a label
+// containing a literal comma (enum('a,b','c')) isn't mis-split, and
+// unescapes MySQL's doubled-quote escaping (enum('it''s','x')) so a
+// label containing a literal quote round-trips correctly.
+func parseEnumColumnType(columnType string) ([]string, error) {
+	inner := strings.TrimSuffix(strings.TrimPrefix(columnType, "enum("), ")")
+
+	var labels []string
+	var current strings.Builder
+	inQuotes := false
+	runes := []rune(inner)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case !inQuotes && c == '\'':
+			inQuotes = true
+		case inQuotes && c == '\'':
+			if i+1 < len(runes) && runes[i+1] == '\'' {
+				// Escaped quote: '' within a quoted label means a literal '.
+				current.WriteRune('\'')
+				i++
+				continue
+			}
+			inQuotes = false
+		case !inQuotes && c == ',':
+			labels = append(labels, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(c)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quoted value in %q", columnType)
+	}
+	labels = append(labels, current.String())
+	return labels, nil
+}
+
+// translateEnumOrdinal rewrites an int64 ordinal decoded from a binlog
+// TABLE_MAP event into its string label for any column tracked in
+// t.EnumValues, so a destination counterpart widened to VARCHAR/TEXT
+// receives the label rather than the source's numeric ordinal. It does
+// not look at the destination column's type. Values that aren't int64,
+// untracked columns and out-of-range ordinals are returned unchanged.
+func translateEnumOrdinal(t *config.Table, column string, value interface{}) interface{} {
+	ordinal, ok := value.(int64)
+	if !ok {
+		return value
+	}
+	if label, ok := t.EnumValueAt(column, int(ordinal)); ok {
+		return label
+	}
+	return value
+}
+
+// validateEnumRemap refuses to start the migration when a shared ENUM
+// column's value ordering differs between source and target, unless the
+// operator has explicitly approved the remap. A differing ordering means
+// the same ordinal means something different on each side, so binding
+// the source's raw ordinal into the target (or naively widening it)
+// would silently corrupt historical rows.
+func validateEnumRemap(t *config.Table, column string, sourceOrder, targetOrder []string, approveEnumRemap bool) error {
+	if enumOrderEqual(sourceOrder, targetOrder) {
+		return nil
+	}
+	if !approveEnumRemap {
+		return fmt.Errorf(
+			"column %s.%s is an ENUM whose value ordering differs between source (%v) and target (%v); "+
+				"set ApproveEnumRemap to proceed",
+			t.Name, column, sourceOrder, targetOrder)
+	}
+	return nil
+}
+
+func enumOrderEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+
+	"udup/internal/config"
+)
+
+func TestParseEnumColumnType(t *testing.T) {
+	cases := []struct {
+		columnType string
+		want       []string
+	}{
+		{`enum('small','medium','large')`, []string{"small", "medium", "large"}},
+		{`enum('a,b','c')`, []string{"a,b", "c"}},
+		{`enum('it''s','x')`, []string{"it's", "x"}},
+		{`enum('')`, []string{""}},
+	}
+
+	for _, c := range cases {
+		got, err := parseEnumColumnType(c.columnType)
+		if err != nil {
+			t.Fatalf("parseEnumColumnType(%q) returned error: %v", c.columnType, err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("parseEnumColumnType(%q) = %#v, want %#v", c.columnType, got, c.want)
+		}
+	}
+}
+
+func TestParseEnumColumnTypeUnterminatedQuote(t *testing.T) {
+	if _, err := parseEnumColumnType(`enum('small,'medium')`); err == nil {
+		t.Fatal("expected an error for an unterminated quoted value")
+	}
+}
+
+func TestEnumValueAtRoundTripsCommaContainingLabel(t *testing.T) {
+	labels, err := parseEnumColumnType(`enum('a,b','c')`)
+	if err != nil {
+		t.Fatalf("parseEnumColumnType error: %v", err)
+	}
+	tbl := &config.Table{EnumValues: map[string][]string{"size": labels}}
+
+	if label, ok := tbl.EnumValueAt("size", 1); !ok || label != "a,b" {
+		t.Errorf("EnumValueAt(size, 1) = (%q, %v), want (\"a,b\", true)", label, ok)
+	}
+	if label, ok := tbl.EnumValueAt("size", 2); !ok || label != "c" {
+		t.Errorf("EnumValueAt(size, 2) = (%q, %v), want (\"c\", true)", label, ok)
+	}
+}
+
+func TestEnumValueAtOrdinalZeroIsEmptyStringNotNotFound(t *testing.T) {
+	tbl := &config.Table{EnumValues: map[string][]string{"size": {"small", "large"}}}
+
+	label, ok := tbl.EnumValueAt("size", 0)
+	if !ok {
+		t.Fatal("EnumValueAt(size, 0) should be ok=true (MySQL's empty-string error value), got false")
+	}
+	if label != "" {
+		t.Errorf("EnumValueAt(size, 0) label = %q, want \"\"", label)
+	}
+
+	if _, ok := tbl.EnumValueAt("untracked", 0); ok {
+		t.Error("EnumValueAt on an untracked column should report ok=false")
+	}
+}
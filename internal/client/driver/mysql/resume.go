@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"sync/atomic"
+
+	gomysql "github.com/siddontang/go-mysql/mysql"
+
+	"udup/internal/config"
+)
+
+// resumeFromCheckpoint looks for a prior checkpoint matching
+// mysqlContext.Uuid and, if either the source's current GTID set or the
+// checkpointed one contains the other (i.e. the master hasn't been
+// rebuilt since), rewinds the row-copy and binlog phases to resume from
+// it instead of starting the migration over. Sets that overlap without
+// either containing the other are treated like disjoint ones and the
+// migration starts over. It's a no-op, returning (false, nil), on a
+// job's first run.
+func (d *Extractor) resumeFromCheckpoint(sourceGtidSet string) (bool, error) {
+	cp, err := config.LoadCheckpoint(d.clientConfig.StateDir, d.mysqlContext.Uuid)
+	if err != nil {
+		return false, err
+	}
+	if cp == nil {
+		return false, nil
+	}
+
+	sourceSet, err := gomysql.ParseMysqlGTIDSet(sourceGtidSet)
+	if err != nil {
+		return false, err
+	}
+	checkpointSet, err := gomysql.ParseMysqlGTIDSet(cp.Gtid)
+	if err != nil {
+		return false, err
+	}
+	if !sourceSet.Contain(checkpointSet) && !checkpointSet.Contain(sourceSet) {
+		// Neither GTID set contains the other; the master was likely
+		// rebuilt. Starting over is safer than
+		// resuming from stale coordinates.
+		return false, nil
+	}
+
+	for _, t := range d.tables {
+		if minValues, ok := cp.TableIterationRangeMinValues[t.Name]; ok {
+			t.SetMigrationIterationRangeMinValues(minValues)
+		}
+	}
+	d.mysqlContext.SetRecentBinlogCoordinates(cp.BinlogCoordinates)
+	d.mysqlContext.Gtid = cp.Gtid
+	atomic.StoreInt64(&d.mysqlContext.TotalRowsCopied, cp.TotalRowsCopied)
+	atomic.StoreInt64(&d.mysqlContext.TotalDMLEventsApplied, cp.TotalDMLEventsApplied)
+
+	return true, nil
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"os"
+	"testing"
+
+	"udup/internal/config"
+)
+
+func newTestExtractorForResume(t *testing.T, tables []*config.Table) *Extractor {
+	t.Helper()
+	stateDir, err := os.MkdirTemp("", "dtle-resume-test")
+	if err != nil {
+		t.Fatalf("failed to create temp StateDir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(stateDir) })
+
+	mysqlContext := config.NewMySQLDriverConfig()
+	return &Extractor{
+		mysqlContext: mysqlContext,
+		clientConfig: &config.ClientConfig{StateDir: stateDir},
+		tables:       tables,
+	}
+}
+
+// TestResumeFromCheckpointFirstRun verifies a job with no prior checkpoint
+// starts fresh rather than erroring.
+func TestResumeFromCheckpointFirstRun(t *testing.T) {
+	d := newTestExtractorForResume(t, nil)
+
+	resumed, err := d.resumeFromCheckpoint("00000000-0000-0000-0000-000000000000:1-5")
+	if err != nil {
+		t.Fatalf("resumeFromCheckpoint returned error: %v", err)
+	}
+	if resumed {
+		t.Fatal("resumeFromCheckpoint() = true on a job's first run, want false")
+	}
+}
+
+// TestResumeFromCheckpointIntersectingGtidSet verifies a checkpoint whose
+// GTID set still intersects the source's current set is resumed from.
+func TestResumeFromCheckpointIntersectingGtidSet(t *testing.T) {
+	tbl := &config.Table{Name: "orders"}
+	d := newTestExtractorForResume(t, []*config.Table{tbl})
+
+	const sid = "00000000-0000-0000-0000-000000000000"
+	if err := d.mysqlContext.SaveCheckpoint(d.clientConfig.StateDir, []*config.Table{tbl}); err != nil {
+		t.Fatalf("SaveCheckpoint failed: %v", err)
+	}
+	d.mysqlContext.Gtid = sid + ":1-5"
+	if err := d.mysqlContext.SaveCheckpoint(d.clientConfig.StateDir, []*config.Table{tbl}); err != nil {
+		t.Fatalf("SaveCheckpoint failed: %v", err)
+	}
+
+	resumed, err := d.resumeFromCheckpoint(sid + ":1-10")
+	if err != nil {
+		t.Fatalf("resumeFromCheckpoint returned error: %v", err)
+	}
+	if !resumed {
+		t.Fatal("resumeFromCheckpoint() = false for an intersecting GTID set, want true")
+	}
+	if d.mysqlContext.Gtid != sid+":1-5" {
+		t.Errorf("Gtid = %q after resume, want checkpointed value %q", d.mysqlContext.Gtid, sid+":1-5")
+	}
+}
+
+// TestResumeFromCheckpointDisjointGtidSet verifies a checkpoint whose GTID
+// set no longer overlaps the source's (e.g. the master was rebuilt) is
+// rejected in favor of starting over, rather than resuming from stale
+// coordinates.
+func TestResumeFromCheckpointDisjointGtidSet(t *testing.T) {
+	tbl := &config.Table{Name: "orders"}
+	d := newTestExtractorForResume(t, []*config.Table{tbl})
+
+	const checkpointedSid = "00000000-0000-0000-0000-000000000000"
+	const rebuiltSid = "11111111-1111-1111-1111-111111111111"
+
+	d.mysqlContext.Gtid = checkpointedSid + ":1-5"
+	if err := d.mysqlContext.SaveCheckpoint(d.clientConfig.StateDir, []*config.Table{tbl}); err != nil {
+		t.Fatalf("SaveCheckpoint failed: %v", err)
+	}
+
+	resumed, err := d.resumeFromCheckpoint(rebuiltSid + ":1-10")
+	if err != nil {
+		t.Fatalf("resumeFromCheckpoint returned error: %v", err)
+	}
+	if resumed {
+		t.Fatal("resumeFromCheckpoint() = true for a disjoint GTID set, want false")
+	}
+}
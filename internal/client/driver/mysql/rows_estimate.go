@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+
+	"udup/internal/config"
+)
+
+// estimateTableRows dispatches to the row-count estimation strategy
+// selected by RowsEstimateMethod. schema is the table's owning database,
+// taken from the enclosing DataSource (config.Table itself carries no
+// schema). TableStatusRowsEstimate and ExplainRowsEstimate block the
+// caller with a cheap, approximate result; CountRowsEstimate kicks off an
+// exact count in the background and returns immediately unless
+// ConcurrentCountTableRows is false, in which case it blocks until the
+// count completes.
+func (d *Extractor) estimateTableRows(db *sql.DB, schema string, t *config.Table) error {
+	switch d.mysqlContext.RowsEstimateMethod {
+	case config.ExplainRowsEstimate:
+		return d.estimateTableRowsViaExplain(db, schema, t)
+	case config.CountRowsEstimate:
+		return d.estimateTableRowsViaCount(db, schema, t)
+	default:
+		return d.estimateTableRowsViaTableStatus(db, schema, t)
+	}
+}
+
+func (d *Extractor) estimateTableRowsViaTableStatus(db *sql.DB, schema string, t *config.Table) error {
+	var rows int64
+	query := `select table_rows from information_schema.tables where table_schema = ? and table_name = ?`
+	if err := db.QueryRow(query, schema, t.Name).Scan(&rows); err != nil {
+		return fmt.Errorf("failed to read TABLE_ROWS for %s.%s: %v", schema, t.Name, err)
+	}
+	t.SetRowsEstimate(rows)
+	return nil
+}
+
+// estimateTableRowsViaExplain runs EXPLAIN against the same predicate the
+// row-copy chunker will use, so the estimate reflects the actual slice of
+// the table being migrated rather than the whole table.
+func (d *Extractor) estimateTableRowsViaExplain(db *sql.DB, schema string, t *config.Table) error {
+	query := fmt.Sprintf("explain select * from `%s`.`%s` where %s", schema, t.Name, d.rangePredicate(t))
+	rows, err := db.Query(query)
+	if err != nil {
+		return fmt.Errorf("failed to EXPLAIN %s: %v", t.Name, err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	dest := make([]sql.RawBytes, len(cols))
+	scanArgs := make([]interface{}, len(cols))
+	for i := range dest {
+		scanArgs[i] = &dest[i]
+	}
+	rowsIdx := -1
+	for i, c := range cols {
+		if c == "rows" {
+			rowsIdx = i
+		}
+	}
+	if rowsIdx == -1 {
+		return fmt.Errorf("EXPLAIN output for %s.%s had no rows column", schema, t.Name)
+	}
+	if rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return err
+		}
+		var estimate int64
+		fmt.Sscanf(string(dest[rowsIdx]), "%d", &estimate)
+		t.SetRowsEstimate(estimate)
+	}
+	return rows.Err()
+}
+
+// estimateTableRowsViaCount issues an exact SELECT COUNT(*) on a
+// throttled goroutine. When ConcurrentCountTableRows is false, row-copy
+// start blocks until the count finishes; otherwise it returns right away
+// and lets the planner start chunking against a provisional estimate.
+func (d *Extractor) estimateTableRowsViaCount(db *sql.DB, schema string, t *config.Table) error {
+	done := make(chan error, 1)
+	go func() {
+		d.throttler.throttleCountTableRows()
+
+		var rows int64
+		query := fmt.Sprintf("select count(*) from `%s`.`%s`", schema, t.Name)
+		err := db.QueryRow(query).Scan(&rows)
+		if err == nil {
+			t.SetRowsEstimate(rows)
+		}
+		done <- err
+	}()
+
+	if !d.mysqlContext.ConcurrentCountTableRows {
+		return <-done
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/armon/go-metrics"
+
+	"udup/internal/config"
+)
+
+// throttleCheck runs one throttler iteration: it evaluates the
+// user-commanded throttle flag, hibernation state, replica lag threshold and
+// critical load, in that priority order, caches the result on
+// mysqlContext so the applier and extractor can consult it without
+// re-running the checks, and logs/emits metrics at a level driven by the
+// reason hint.
+func (d *Extractor) throttleCheck() *config.ThrottleCheckResult {
+	var result *config.ThrottleCheckResult
+	switch {
+	case d.throttler.userCommandedThrottle():
+		result = config.NewThrottleCheckResult(true, "commanded by user", config.UserCommandThrottleReasonHint)
+	case d.throttler.leavingHibernation():
+		result = config.NewThrottleCheckResult(true, "leaving hibernation", config.LeavingHibernationThrottleReasonHint)
+	case d.replicaLagExceeded():
+		result = config.NewThrottleCheckResult(true, "replica lag exceeded threshold", config.ReplicaLagThrottleReasonHint)
+	case d.throttler.criticalLoadExceeded():
+		result = config.NewThrottleCheckResult(true, "critical load exceeded", config.CriticalLoadThrottleReasonHint)
+	default:
+		result = config.NewThrottleCheckResult(false, "", config.NoThrottleReasonHint)
+	}
+
+	d.mysqlContext.SetThrottleStatus(result)
+	logThrottleStatus(d.logger, result)
+	emitThrottleMetrics(result)
+	return result
+}
+
+// replicaLagExceeded reports whether the replica's current lag has
+// crossed MaxLagMillisecondsThrottleThreshold. A threshold of 0 disables
+// the check.
+func (d *Extractor) replicaLagExceeded() bool {
+	threshold := d.mysqlContext.MaxLagMillisecondsThrottleThreshold
+	if threshold <= 0 {
+		return false
+	}
+	return d.throttler.replicaLagMilliseconds() >= threshold
+}
+
+// checkThrottleStatus is the applier-side counterpart to throttleCheck:
+// the applier doesn't run its own throttler iteration, but it consults
+// the cached ThrottleCheckResult on every apply cycle to decide log level
+// and metrics, same as the extractor.
+func (d *Applier) checkThrottleStatus() *config.ThrottleCheckResult {
+	result := d.mysqlContext.GetThrottleStatus()
+	logThrottleStatus(d.logger, result)
+	emitThrottleMetrics(result)
+	return result
+}
+
+// loggerInterface is the subset of the driver's logger that
+// logThrottleStatus needs; both Extractor and Applier already embed a
+// logger satisfying it.
+type loggerInterface interface {
+	Warningf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+}
+
+// logThrottleStatus logs at warn for conditions an operator should act on
+// (critical load, replica lag) and info for routine, expected pauses (a
+// user-commanded throttle, or the brief hibernation hand-off).
+func logThrottleStatus(logger loggerInterface, result *config.ThrottleCheckResult) {
+	if !result.ShouldThrottle {
+		return
+	}
+	msg := fmt.Sprintf("throttling: %s", result.Reason)
+	switch result.ReasonHint {
+	case config.CriticalLoadThrottleReasonHint, config.ReplicaLagThrottleReasonHint:
+		logger.Warningf(msg)
+	default:
+		logger.Infof(msg)
+	}
+}
+
+// emitThrottleMetrics reports a counter per throttle reason so operators
+// can graph how often (and why) migrations are being held back.
+func emitThrottleMetrics(result *config.ThrottleCheckResult) {
+	if !result.ShouldThrottle {
+		return
+	}
+	metrics.IncrCounter([]string{"dtle", "throttle", throttleReasonHintLabel(result.ReasonHint)}, 1)
+}
+
+func throttleReasonHintLabel(hint config.ThrottleReasonHint) string {
+	switch hint {
+	case config.UserCommandThrottleReasonHint:
+		return "user_command"
+	case config.LeavingHibernationThrottleReasonHint:
+		return "leaving_hibernation"
+	case config.ReplicaLagThrottleReasonHint:
+		return "replica_lag"
+	case config.CriticalLoadThrottleReasonHint:
+		return "critical_load"
+	default:
+		return "none"
+	}
+}
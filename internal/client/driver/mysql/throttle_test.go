@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"udup/internal/config"
+)
+
+// discardLogger satisfies loggerInterface without writing anywhere.
+type discardLogger struct{}
+
+func (discardLogger) Warningf(format string, args ...interface{}) {}
+func (discardLogger) Infof(format string, args ...interface{})    {}
+
+func newTestExtractorForThrottle() *Extractor {
+	mysqlContext := config.NewMySQLDriverConfig()
+	return &Extractor{
+		mysqlContext: mysqlContext,
+		throttler:    newThrottler(mysqlContext),
+		logger:       discardLogger{},
+	}
+}
+
+// TestThrottleCheckPriority verifies throttleCheck evaluates conditions in
+// the documented priority order: a user command wins over leaving
+// hibernation, which wins over replica lag, which wins over critical load.
+func TestThrottleCheckPriority(t *testing.T) {
+	d := newTestExtractorForThrottle()
+	d.mysqlContext.MaxLagMillisecondsThrottleThreshold = 1000
+	d.throttler.setUserCommandedThrottle(true)
+	d.throttler.setLeavingHibernation(true)
+	atomic.StoreInt32(&d.throttler.criticalLoadTripped, 1)
+	atomic.StoreInt64(&d.throttler.replicaLagMs, 5000)
+
+	result := d.throttleCheck()
+	if result.ReasonHint != config.UserCommandThrottleReasonHint {
+		t.Fatalf("ReasonHint = %v, want UserCommandThrottleReasonHint", result.ReasonHint)
+	}
+
+	d.throttler.setUserCommandedThrottle(false)
+	result = d.throttleCheck()
+	if result.ReasonHint != config.LeavingHibernationThrottleReasonHint {
+		t.Fatalf("ReasonHint = %v, want LeavingHibernationThrottleReasonHint", result.ReasonHint)
+	}
+
+	d.throttler.setLeavingHibernation(false)
+	result = d.throttleCheck()
+	if result.ReasonHint != config.ReplicaLagThrottleReasonHint {
+		t.Fatalf("ReasonHint = %v, want ReplicaLagThrottleReasonHint", result.ReasonHint)
+	}
+
+	atomic.StoreInt64(&d.throttler.replicaLagMs, 0)
+	result = d.throttleCheck()
+	if result.ReasonHint != config.CriticalLoadThrottleReasonHint {
+		t.Fatalf("ReasonHint = %v, want CriticalLoadThrottleReasonHint", result.ReasonHint)
+	}
+
+	atomic.StoreInt32(&d.throttler.criticalLoadTripped, 0)
+	result = d.throttleCheck()
+	if result.ShouldThrottle {
+		t.Fatalf("ShouldThrottle = true, want false once every condition clears")
+	}
+	if result.ReasonHint != config.NoThrottleReasonHint {
+		t.Fatalf("ReasonHint = %v, want NoThrottleReasonHint", result.ReasonHint)
+	}
+}
+
+// TestReplicaLagExceededThresholdDisabled verifies a zero threshold
+// disables the lag check instead of always tripping on any reported lag.
+func TestReplicaLagExceededThresholdDisabled(t *testing.T) {
+	d := newTestExtractorForThrottle()
+	atomic.StoreInt64(&d.throttler.replicaLagMs, 60000)
+
+	if d.replicaLagExceeded() {
+		t.Fatal("replicaLagExceeded() = true with MaxLagMillisecondsThrottleThreshold unset, want false")
+	}
+
+	d.mysqlContext.MaxLagMillisecondsThrottleThreshold = 1000
+	if !d.replicaLagExceeded() {
+		t.Fatal("replicaLagExceeded() = false with lag past threshold, want true")
+	}
+}
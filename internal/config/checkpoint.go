@@ -0,0 +1,123 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	ubase "udup/internal/client/driver/mysql/base"
+	umconf "udup/internal/config/mysql"
+)
+
+// Checkpoint is the on-disk resume state for one migration job, keyed by
+// MySQLDriverConfig.Uuid. It's persisted under ClientConfig.StateDir so a
+// dtle agent restart can resume a multi-hour migration instead of
+// starting over.
+type Checkpoint struct {
+	Uuid                  string
+	Gtid                  string
+	BinlogCoordinates     ubase.BinlogCoordinates
+	TotalRowsCopied       int64
+	TotalDMLEventsApplied int64
+
+	// TableIterationRangeMinValues is keyed by table name, mirroring
+	// Table.MigrationIterationRangeMinValues for every table in flight
+	// when the checkpoint was taken.
+	TableIterationRangeMinValues map[string]*umconf.ColumnValues
+}
+
+// checkpointDir is where checkpoint files live under StateDir.
+func checkpointDir(stateDir string) string {
+	return filepath.Join(stateDir, "checkpoints")
+}
+
+func checkpointPath(stateDir, uuid string) string {
+	return filepath.Join(checkpointDir(stateDir), uuid+".json")
+}
+
+// SaveCheckpoint snapshots the driver's current resume state and writes
+// it to StateDir, keyed by m.Uuid. tables should be the set of tables
+// currently in (or pending) row-copy.
+func (m *MySQLDriverConfig) SaveCheckpoint(stateDir string, tables []*Table) error {
+	if err := os.MkdirAll(checkpointDir(stateDir), 0755); err != nil {
+		return fmt.Errorf("failed to create checkpoint dir: %v", err)
+	}
+
+	cp := &Checkpoint{
+		Uuid:                         m.Uuid,
+		Gtid:                         m.Gtid,
+		BinlogCoordinates:            m.GetRecentBinlogCoordinates(),
+		TotalRowsCopied:              m.GetTotalRowsCopied(),
+		TotalDMLEventsApplied:        atomic.LoadInt64(&m.TotalDMLEventsApplied),
+		TableIterationRangeMinValues: make(map[string]*umconf.ColumnValues, len(tables)),
+	}
+	for _, t := range tables {
+		cp.TableIterationRangeMinValues[t.Name] = t.GetMigrationIterationRangeMinValues()
+	}
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %v", err)
+	}
+
+	tmp := checkpointPath(stateDir, m.Uuid) + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %v", err)
+	}
+	return os.Rename(tmp, checkpointPath(stateDir, m.Uuid))
+}
+
+// LoadCheckpoint reads back a previously saved Checkpoint for uuid. It
+// returns (nil, nil) if no checkpoint exists yet, which is the normal
+// case for a job's first run.
+func LoadCheckpoint(stateDir, uuid string) (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(checkpointPath(stateDir, uuid))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %v", err)
+	}
+
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint: %v", err)
+	}
+	return cp, nil
+}
+
+// StartCheckpointing persists a Checkpoint every interval until stopCh is
+// closed. Call it once the job's UUID, tables, and StateDir are known.
+// onError is called with any SaveCheckpoint failure instead of it being
+// silently dropped; pass nil to log to stderr.
+func (m *MySQLDriverConfig) StartCheckpointing(stateDir string, tables []*Table, interval time.Duration, stopCh <-chan struct{}, onError func(error)) {
+	if onError == nil {
+		onError = func(err error) {
+			fmt.Fprintf(os.Stderr, "checkpoint: failed to save checkpoint for job %s: %v\n", m.Uuid, err)
+		}
+	}
+
+	save := func() {
+		if err := m.SaveCheckpoint(stateDir, tables); err != nil {
+			onError(err)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				save()
+			case <-stopCh:
+				save()
+				return
+			}
+		}
+	}()
+}
@@ -7,6 +7,9 @@ import (
 
 	"sync"
 	"sync/atomic"
+
+	uuid "github.com/satori/go.uuid"
+
 	"udup/internal"
 	ubase "udup/internal/client/driver/mysql/base"
 	umconf "udup/internal/config/mysql"
@@ -19,6 +22,13 @@ const (
 	DefaultClusterID     = "udup-cluster"
 )
 
+// Defaults for the binlog syncer's liveness detection. BinlogSyncerMaxReconnectAttempts
+// has no default since 0 (retry forever) is itself a meaningful, historical value.
+const (
+	DefaultBinlogHeartbeatPeriod = 5 * time.Second
+	DefaultBinlogReadTimeout     = 30 * time.Second
+)
+
 // RPCHandler can be provided to the Client if there is a local server
 // to avoid going over the network. If not provided, the Client will
 // maintain a connection pool to the servers
@@ -110,9 +120,70 @@ const (
 	CutOverTwoStep         = iota
 )
 
+// RowsEstimateMethod selects how the row-copy planner estimates the number
+// of rows in a table before chunking it.
+type RowsEstimateMethod string
+
+const (
+	// TableStatusRowsEstimate reads information_schema.TABLES.TABLE_ROWS.
+	// It's cheap and approximate, and the default for every table.
+	TableStatusRowsEstimate RowsEstimateMethod = "TableStatusRowsEstimate"
+	// ExplainRowsEstimate runs EXPLAIN against the actual chunking
+	// predicate and takes the optimizer's `rows` estimate. Costs one
+	// query per table but tracks the real predicate more closely than
+	// TABLE_ROWS.
+	ExplainRowsEstimate RowsEstimateMethod = "ExplainRowsEstimate"
+	// CountRowsEstimate issues SELECT COUNT(*) under a throttled
+	// goroutine and updates Table.RowsEstimate asynchronously. Exact,
+	// but unaffordable to wait on for billion-row tables.
+	CountRowsEstimate RowsEstimateMethod = "CountRowsEstimate"
+)
+
+// ThrottleReasonHint classifies why the throttler tripped, so callers can
+// decide log level and metrics without string-matching Reason.
+type ThrottleReasonHint int
+
+const (
+	NoThrottleReasonHint ThrottleReasonHint = iota
+	UserCommandThrottleReasonHint
+	LeavingHibernationThrottleReasonHint
+	ReplicaLagThrottleReasonHint
+	CriticalLoadThrottleReasonHint
+)
+
+// ThrottleCheckResult is the outcome of one throttler iteration: whether
+// to throttle, and why, so operators can distinguish "user paused via
+// flag file" from "replica lag exceeded" from "critical load tripped".
+type ThrottleCheckResult struct {
+	ShouldThrottle bool
+	Reason         string
+	ReasonHint     ThrottleReasonHint
+}
+
+// NewThrottleCheckResult builds a ThrottleCheckResult, defaulting
+// ReasonHint to NoThrottleReasonHint when not throttling.
+func NewThrottleCheckResult(shouldThrottle bool, reason string, reasonHint ThrottleReasonHint) *ThrottleCheckResult {
+	if !shouldThrottle {
+		reasonHint = NoThrottleReasonHint
+	}
+	return &ThrottleCheckResult{
+		ShouldThrottle: shouldThrottle,
+		Reason:         reason,
+		ReasonHint:     reasonHint,
+	}
+}
+
 type MySQLDriverConfig struct {
 	//Ref:http://dev.mysql.com/doc/refman/5.7/en/replication-options-slave.html#option_mysqld_replicate-do-table
 	ReplicateDoDb                       []*DataSource
+	// RowsEstimateMethod selects the strategy used to estimate a table's
+	// row count before chunking it. Defaults to TableStatusRowsEstimate.
+	RowsEstimateMethod RowsEstimateMethod
+	// ConcurrentCountTableRows, when RowsEstimateMethod is
+	// CountRowsEstimate, controls whether row-copy start blocks on the
+	// COUNT(*) completing (false) or begins chunking immediately with a
+	// provisional estimate that's corrected once the count finishes
+	// (true).
 	ConcurrentCountTableRows            bool
 	SkipRenamedColumns                  bool
 	MaxRetries                          int64
@@ -121,11 +192,33 @@ type MySQLDriverConfig struct {
 	MaxLagMillisecondsThrottleThreshold int64
 	maxLoad                             umconf.LoadMap
 	criticalLoad                        umconf.LoadMap
+	throttleCheckResult                 *ThrottleCheckResult
 	PostponeCutOverFlagFile             string
 	CutOverLockTimeoutSeconds           int64
 	RowsDeltaEstimate                   int64
 	TimeZone                            string
 
+	// BinlogSyncerMaxReconnectAttempts bounds how many consecutive times the
+	// binlog reader will reconnect to a broken upstream connection before
+	// giving up and surfacing a terminal error to the driver. 0 means retry
+	// forever, matching the historical behavior.
+	BinlogSyncerMaxReconnectAttempts int
+	// BinlogHeartbeatPeriod is how often the syncer asks the master for a
+	// heartbeat so a dead TCP connection is detected within a bounded
+	// interval instead of waiting on TCP keepalive.
+	BinlogHeartbeatPeriod time.Duration
+	// BinlogReadTimeout is the maximum time to wait for a binlog event
+	// before treating the connection as hung.
+	BinlogReadTimeout time.Duration
+	// BinlogSyncerServerID is the server-id the syncer registers with the
+	// master as. It must be unique among all replicas and dtle agents
+	// connected to that master.
+	BinlogSyncerServerID uint32
+
+	// Uuid uniquely identifies this migration job across driver restarts.
+	// It's generated once at job construction and used as the checkpoint
+	// key persisted under ClientConfig.StateDir.
+	Uuid                     string
 	Gtid                     string
 	NatsAddr                 string
 	ParallelWorkers          int
@@ -147,6 +240,11 @@ type MySQLDriverConfig struct {
 
 	CutOverType          CutOver
 	ApproveHeterogeneous bool
+	// ApproveEnumRemap must be set to proceed when a shared ENUM column's
+	// value ordering differs between source and target; otherwise setup
+	// refuses to start rather than risk silently corrupting rows that
+	// rely on the source's ordinal-to-label mapping.
+	ApproveEnumRemap bool
 
 	throttleMutex                          *sync.Mutex
 	IsPostponingCutOver                    int64
@@ -168,9 +266,32 @@ func (a *MySQLDriverConfig) SetDefault() *MySQLDriverConfig {
 	if result.ParallelWorkers == 0 {
 		result.ParallelWorkers = 1
 	}
+	if result.BinlogHeartbeatPeriod == 0 {
+		result.BinlogHeartbeatPeriod = DefaultBinlogHeartbeatPeriod
+	}
+	if result.BinlogReadTimeout == 0 {
+		result.BinlogReadTimeout = DefaultBinlogReadTimeout
+	}
+	if result.RowsEstimateMethod == "" {
+		result.RowsEstimateMethod = TableStatusRowsEstimate
+	}
 	return &result
 }
 
+// NewMySQLDriverConfig builds a MySQLDriverConfig for a brand-new job,
+// generating its Uuid once. The Uuid is the checkpoint key for the
+// lifetime of the job, so callers must persist it immediately and, on
+// every subsequent load of this job (e.g. after a driver restart),
+// populate Uuid from that persisted value rather than calling this again
+// — generating it here instead of in SetDefault is what keeps a reload
+// from minting a fresh Uuid and orphaning the job's checkpoint.
+func NewMySQLDriverConfig() *MySQLDriverConfig {
+	return &MySQLDriverConfig{
+		Uuid:          uuid.NewV4().String(),
+		throttleMutex: &sync.Mutex{},
+	}
+}
+
 // RequiresBinlogFormatChange is `true` when the original binlog format isn't `ROW`
 func (m *MySQLDriverConfig) RequiresBinlogFormatChange() bool {
 	return m.BinlogFormat != "ROW"
@@ -196,10 +317,66 @@ func (m *MySQLDriverConfig) SetRecentBinlogCoordinates(coordinates ubase.BinlogC
 	m.recentBinlogCoordinates = coordinates
 }
 
+// GetRecentBinlogCoordinates returns the most recently processed binlog
+// coordinates, used by the checkpoint subsystem to persist resume state.
+func (m *MySQLDriverConfig) GetRecentBinlogCoordinates() ubase.BinlogCoordinates {
+	m.throttleMutex.Lock()
+	defer m.throttleMutex.Unlock()
+	return m.recentBinlogCoordinates
+}
+
 func (m *MySQLDriverConfig) GetIteration() int64 {
 	return atomic.LoadInt64(&m.Iteration)
 }
 
+// SetPostponingCutOver records whether the PostponeCutOverFlagFile is
+// currently present, as polled by the cut-over flag file watcher.
+func (m *MySQLDriverConfig) SetPostponingCutOver(postponing bool) {
+	var v int64
+	if postponing {
+		v = 1
+	}
+	atomic.StoreInt64(&m.IsPostponingCutOver, v)
+}
+
+// IsCutOverPostponed reports whether the cut-over is currently being held
+// back by the flag file (and the user hasn't forced progression).
+func (m *MySQLDriverConfig) IsCutOverPostponed() bool {
+	return atomic.LoadInt64(&m.IsPostponingCutOver) == 1 && !m.IsUnpostponeCommanded()
+}
+
+// CommandUnpostponeCutOver is called from the client agent's RPC
+// endpoint to force the cut-over to proceed even while the postpone flag
+// file is still present.
+func (m *MySQLDriverConfig) CommandUnpostponeCutOver() {
+	atomic.StoreInt64(&m.UserCommandedUnpostponeFlag, 1)
+}
+
+// IsUnpostponeCommanded reports whether the user has forced the cut-over
+// to proceed via CommandUnpostponeCutOver.
+func (m *MySQLDriverConfig) IsUnpostponeCommanded() bool {
+	return atomic.LoadInt64(&m.UserCommandedUnpostponeFlag) == 1
+}
+
+// EnterCutOverCriticalSection marks the start of the rename/metadata-lock
+// critical section. While set, SIGTERM-based shutdown must be rejected so
+// the migration isn't killed mid-rename.
+func (m *MySQLDriverConfig) EnterCutOverCriticalSection() {
+	atomic.StoreInt64(&m.InCutOverCriticalSectionFlag, 1)
+}
+
+// LeaveCutOverCriticalSection clears the critical section flag, e.g. once
+// the rename commits or CutOverLockTimeoutSeconds forces a rollback.
+func (m *MySQLDriverConfig) LeaveCutOverCriticalSection() {
+	atomic.StoreInt64(&m.InCutOverCriticalSectionFlag, 0)
+}
+
+// IsInCutOverCriticalSection reports whether a SIGTERM should currently
+// be rejected rather than honored.
+func (m *MySQLDriverConfig) IsInCutOverCriticalSection() bool {
+	return atomic.LoadInt64(&m.InCutOverCriticalSectionFlag) == 1
+}
+
 func (m *MySQLDriverConfig) TimeSincePointOfInterest() time.Duration {
 	m.pointOfInterestTimeMutex.Lock()
 	defer m.pointOfInterestTimeMutex.Unlock()
@@ -255,6 +432,28 @@ func (m *MySQLDriverConfig) GetCriticalLoad() umconf.LoadMap {
 	return m.criticalLoad.Duplicate()
 }
 
+// SetThrottleStatus caches the outcome of the latest throttler iteration.
+// Called once per iteration by the throttler; read by the applier and
+// extractor via GetThrottleStatus to decide log level and metrics.
+func (m *MySQLDriverConfig) SetThrottleStatus(result *ThrottleCheckResult) {
+	m.throttleMutex.Lock()
+	defer m.throttleMutex.Unlock()
+
+	m.throttleCheckResult = result
+}
+
+// GetThrottleStatus returns the most recently cached ThrottleCheckResult.
+// Before the first throttler iteration has run, it reports no throttling.
+func (m *MySQLDriverConfig) GetThrottleStatus() *ThrottleCheckResult {
+	m.throttleMutex.Lock()
+	defer m.throttleMutex.Unlock()
+
+	if m.throttleCheckResult == nil {
+		return NewThrottleCheckResult(false, "", NoThrottleReasonHint)
+	}
+	return m.throttleCheckResult
+}
+
 func (m *MySQLDriverConfig) MarkPointOfInterest() int64 {
 	//m.pointOfInterestTimeMutex.Lock()
 	//defer m.pointOfInterestTimeMutex.Unlock()
@@ -294,8 +493,73 @@ type Table struct {
 	MigrationIterationRangeMinValues *umconf.ColumnValues
 	MigrationIterationRangeMaxValues *umconf.ColumnValues
 
+	// EnumValues maps the name of each ENUM column on the source table to
+	// its ordered list of value labels (as captured from
+	// information_schema.COLUMNS.COLUMN_TYPE, e.g. []string{"small",
+	// "medium", "large"} from "enum('small','medium','large')"). Stored as
+	// a slice rather than a joined string so a label containing a literal
+	// comma can't be mis-split on the way back out. It lets the applier
+	// translate the numeric ordinal decoded from a binlog TABLE_MAP event
+	// back into its string label when the destination column has been
+	// widened to VARCHAR/TEXT.
+	EnumValues map[string][]string
+
 	TableEngine  string
 	RowsEstimate int64
+
+	// iterationRangeMutex guards MigrationIterationRangeMinValues, which
+	// the row-copy loop advances on every chunk while the checkpoint
+	// subsystem reads it concurrently from a separate goroutine.
+	iterationRangeMutex sync.Mutex
+}
+
+// GetMigrationIterationRangeMinValues reads MigrationIterationRangeMinValues
+// under iterationRangeMutex, safe to call concurrently with the row-copy
+// loop advancing it via SetMigrationIterationRangeMinValues.
+func (t *Table) GetMigrationIterationRangeMinValues() *umconf.ColumnValues {
+	t.iterationRangeMutex.Lock()
+	defer t.iterationRangeMutex.Unlock()
+	return t.MigrationIterationRangeMinValues
+}
+
+// SetMigrationIterationRangeMinValues stores MigrationIterationRangeMinValues
+// under iterationRangeMutex.
+func (t *Table) SetMigrationIterationRangeMinValues(v *umconf.ColumnValues) {
+	t.iterationRangeMutex.Lock()
+	defer t.iterationRangeMutex.Unlock()
+	t.MigrationIterationRangeMinValues = v
+}
+
+// EnumValueAt returns the label for the given ENUM ordinal (as MySQL
+// encodes it on the wire: 0 is the empty-string error value, 1 is the
+// first declared value, and so on) for column. ok is false when column
+// isn't a tracked ENUM column or the ordinal is out of range; callers
+// must check ok rather than testing the label against "", since an
+// ordinal of 0 is itself a legitimate empty-string result.
+func (t *Table) EnumValueAt(column string, ordinal int) (label string, ok bool) {
+	values, tracked := t.EnumValues[column]
+	if !tracked || ordinal < 0 {
+		return "", false
+	}
+	if ordinal == 0 {
+		return "", true
+	}
+	if ordinal > len(values) {
+		return "", false
+	}
+	return values[ordinal-1], true
+}
+
+// GetRowsEstimate atomically reads RowsEstimate. Needed because, under
+// CountRowsEstimate, a throttled goroutine updates it concurrently with
+// the row-copy planner reading it.
+func (t *Table) GetRowsEstimate() int64 {
+	return atomic.LoadInt64(&t.RowsEstimate)
+}
+
+// SetRowsEstimate atomically stores RowsEstimate.
+func (t *Table) SetRowsEstimate(rows int64) {
+	atomic.StoreInt64(&t.RowsEstimate, rows)
 }
 
 // DefaultConfig returns the default configuration